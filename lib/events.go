@@ -0,0 +1,88 @@
+package nuclei
+
+import (
+	"context"
+
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// eventPublishingWriter wraps an output.Writer so that every ResultEvent
+// written during a scan gets its own "nuclei.result" span linked back to
+// resultSpan, independently of whether a ScanEventCallback is configured -
+// callback may be nil, in which case only the span is produced. When set,
+// callback is also forwarded a ScanEventResult, alongside whatever the
+// wrapped writer does with the event (console output, JSONL file, etc).
+type eventPublishingWriter struct {
+	output.Writer
+	callback   ScanEventCallback
+	resultSpan trace.Span
+}
+
+// Write forwards event to the wrapped writer, publishes a ScanEventResult if
+// a callback is set, and records a span if resultSpan is set.
+func (w *eventPublishingWriter) Write(event *output.ResultEvent) error {
+	if w.resultSpan != nil {
+		parentCtx := trace.ContextWithSpan(context.Background(), w.resultSpan)
+		_, span := w.resultSpan.TracerProvider().Tracer(tracerName).Start(
+			parentCtx, "nuclei.result",
+			trace.WithAttributes(attribute.String("nuclei.template_id", event.TemplateID)),
+		)
+		defer span.End()
+	}
+	if w.callback != nil {
+		w.callback(&ScanEvent{Type: ScanEventResult, Result: event})
+	}
+	return w.Writer.Write(event)
+}
+
+// ScanEventType identifies the kind of lifecycle event a running scan emits.
+type ScanEventType string
+
+const (
+	// ScanEventStarted is emitted once, right before templates start executing.
+	ScanEventStarted ScanEventType = "started"
+	// ScanEventProgress identifies a progress tick carrying TemplatesExecuted.
+	// ExecuteNucleiWithOptsCtx does not emit it: a scan produces only
+	// started, result and finished events.
+	ScanEventProgress ScanEventType = "progress"
+	// ScanEventResult is emitted for every output.ResultEvent produced by the scan.
+	ScanEventResult ScanEventType = "result"
+	// ScanEventFinished is emitted once the scan has completed, successfully or not.
+	ScanEventFinished ScanEventType = "finished"
+)
+
+// ScanEvent is a single lifecycle event published while a scan runs. Only
+// the fields relevant to Type are populated.
+type ScanEvent struct {
+	Type ScanEventType
+
+	// TemplatesTotal is set on ScanEventStarted and ScanEventFinished;
+	// TemplatesExecuted only belongs to ScanEventProgress.
+	TemplatesExecuted int
+	TemplatesTotal    int
+
+	// Result is set on ScanEventResult.
+	Result *output.ResultEvent
+
+	// Err is set on ScanEventFinished if the scan ended in error.
+	Err error
+}
+
+// ScanEventCallback is invoked for every ScanEvent a scan produces. It
+// mirrors GlobalResultCallback but carries full lifecycle information
+// instead of just results, so callers can stream scan progress elsewhere
+// (e.g. a pub/sub bus) without polling.
+type ScanEventCallback func(event *ScanEvent)
+
+// WithScanEventCallback sets a callback that is invoked for every
+// lifecycle event (start, result, finished) a scan produces.
+// Unlike GlobalResultCallback it is thread-safe and may be used with
+// ThreadSafeNucleiEngine.
+func WithScanEventCallback(callback ScanEventCallback) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.eventCallback = callback
+		return nil
+	}
+}
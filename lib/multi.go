@@ -15,6 +15,9 @@ import (
 	"github.com/projectdiscovery/nuclei/v3/pkg/types"
 	"github.com/projectdiscovery/ratelimit"
 	errorutil "github.com/projectdiscovery/utils/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // unsafeOptions are those nuclei objects/instances/types
@@ -27,7 +30,10 @@ type unsafeOptions struct {
 }
 
 // createEphemeralObjects creates ephemeral nuclei objects/instances/types
-func createEphemeralObjects(base *NucleiEngine, opts *types.Options) (*unsafeOptions, error) {
+func createEphemeralObjects(ctx context.Context, base *NucleiEngine, opts *types.Options) (*unsafeOptions, error) {
+	_, span := base.tracer().Start(ctx, "nuclei.engine.setup")
+	defer span.End()
+
 	u := &unsafeOptions{}
 	u.executerOpts = protocols.ExecutorOptions{
 		Output:          base.customWriter,
@@ -95,57 +101,137 @@ func (e *ThreadSafeNucleiEngine) GlobalResultCallback(callback func(event *outpu
 // by invoking this method with different options and targets
 // Note: Not all options are thread-safe. this method will throw error if you try to use non-thread-safe options
 func (e *ThreadSafeNucleiEngine) ExecuteNucleiWithOpts(targets []string, opts ...NucleiSDKOptions) error {
+	return e.ExecuteNucleiWithOptsCtx(context.Background(), targets, opts...)
+}
+
+// ExecuteNucleiWithOptsCtx is the context-aware variant of
+// ExecuteNucleiWithOpts. The scan, template loading and target loading each
+// run under their own OTel span, all children of a top-level "nuclei.execute"
+// span, so the whole run is queryable as a single trace.
+func (e *ThreadSafeNucleiEngine) ExecuteNucleiWithOptsCtx(ctx context.Context, targets []string, opts ...NucleiSDKOptions) error {
+	ctx, span := e.eng.tracer().Start(ctx, "nuclei.execute")
+	defer span.End()
+	span.SetAttributes(attribute.Int("nuclei.target_count", len(targets)))
+
 	baseOpts := *e.eng.opts
-	tmpEngine := &NucleiEngine{opts: &baseOpts, mode: threadSafe}
+	tmpEngine := &NucleiEngine{opts: &baseOpts, mode: threadSafe, tracerProvider: e.eng.tracerProvider}
 	for _, option := range opts {
 		if err := option(tmpEngine); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
 			return err
 		}
 	}
-	// create ephemeral nuclei objects/instances/types using base nuclei engine
-	unsafeOpts, err := createEphemeralObjects(e.eng, tmpEngine.opts)
-	if err != nil {
-		return err
+	if tmpEngine.opts.RateLimit > 0 {
+		span.SetAttributes(attribute.Int("nuclei.rate_limit", tmpEngine.opts.RateLimit))
 	}
 
-	// load templates
-	workflowLoader, err := parsers.NewLoader(&unsafeOpts.executerOpts)
+	// create ephemeral nuclei objects/instances/types using base nuclei engine
+	unsafeOpts, err := createEphemeralObjects(ctx, e.eng, tmpEngine.opts)
 	if err != nil {
-		return errorutil.New("Could not create workflow loader: %s\n", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
 	}
-	unsafeOpts.executerOpts.WorkflowLoader = workflowLoader
 
-	store, err := loader.New(loader.NewConfig(tmpEngine.opts, e.eng.catalog, unsafeOpts.executerOpts))
+	store, err := e.loadTemplates(ctx, tmpEngine, unsafeOpts)
 	if err != nil {
-		return errorutil.New("Could not create loader client: %s\n", err)
-	}
-	store.Load()
-
-	inputProvider := &inputs.SimpleInputProvider{
-		Inputs: []*contextargs.MetaInput{},
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
 	}
 
-	// load targets
-	for _, target := range targets {
-		inputProvider.Set(target)
-	}
+	inputProvider := e.loadTargets(ctx, targets)
 
 	if len(store.Templates()) == 0 && len(store.Workflows()) == 0 {
+		span.SetStatus(codes.Error, ErrNoTemplatesAvailable.Error())
 		return ErrNoTemplatesAvailable
 	}
 	if inputProvider.Count() == 0 {
+		span.SetStatus(codes.Error, ErrNoTargetsAvailable.Error())
 		return ErrNoTargetsAvailable
 	}
+	span.SetAttributes(
+		attribute.Int("nuclei.template_count", len(store.Templates())+len(store.Workflows())),
+		attribute.Int("nuclei.input_count", inputProvider.Count()),
+	)
+
+	eventCallback := e.eng.eventCallback
+	totalTemplates := len(store.Templates()) + len(store.Workflows())
+	if eventCallback != nil {
+		eventCallback(&ScanEvent{Type: ScanEventStarted, TemplatesTotal: totalTemplates})
+	}
+
+	// Wrap the writer unconditionally so every output.ResultEvent gets its
+	// own "nuclei.result" span linked back to span, regardless of whether
+	// a ScanEventCallback is set - callback is nil-safe inside Write.
+	unsafeOpts.executerOpts.Output = &eventPublishingWriter{
+		Writer:     unsafeOpts.executerOpts.Output,
+		callback:   eventCallback,
+		resultSpan: span,
+	}
 
 	engine := core.New(tmpEngine.opts)
 	engine.SetExecuterOptions(unsafeOpts.executerOpts)
 
-	_ = engine.ExecuteScanWithOpts(store.Templates(), inputProvider, false)
+	_, scanSpan := e.eng.tracer().Start(ctx, "nuclei.engine.scan", trace.WithAttributes(
+		attribute.Int("nuclei.template_count", totalTemplates),
+	))
+	executeErr := engine.ExecuteScanWithOpts(store.Templates(), inputProvider, false)
 
 	engine.WorkPool().Wait()
+	if executeErr != nil {
+		scanSpan.RecordError(executeErr)
+		scanSpan.SetStatus(codes.Error, executeErr.Error())
+	}
+	scanSpan.End()
+
+	if eventCallback != nil {
+		eventCallback(&ScanEvent{Type: ScanEventFinished, TemplatesTotal: totalTemplates, Err: executeErr})
+	}
 	return nil
 }
 
+// loadTemplates loads templates/workflows under a "nuclei.load_templates" span.
+func (e *ThreadSafeNucleiEngine) loadTemplates(ctx context.Context, tmpEngine *NucleiEngine, unsafeOpts *unsafeOptions) (*loader.Store, error) {
+	_, span := e.eng.tracer().Start(ctx, "nuclei.load_templates")
+	defer span.End()
+
+	workflowLoader, err := parsers.NewLoader(&unsafeOpts.executerOpts)
+	if err != nil {
+		err = errorutil.New("Could not create workflow loader: %s\n", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	unsafeOpts.executerOpts.WorkflowLoader = workflowLoader
+
+	store, err := loader.New(loader.NewConfig(tmpEngine.opts, e.eng.catalog, unsafeOpts.executerOpts))
+	if err != nil {
+		err = errorutil.New("Could not create loader client: %s\n", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	store.Load()
+	return store, nil
+}
+
+// loadTargets builds the input provider under a "nuclei.load_targets" span.
+func (e *ThreadSafeNucleiEngine) loadTargets(ctx context.Context, targets []string) *inputs.SimpleInputProvider {
+	_, span := e.eng.tracer().Start(ctx, "nuclei.load_targets")
+	defer span.End()
+
+	inputProvider := &inputs.SimpleInputProvider{
+		Inputs: []*contextargs.MetaInput{},
+	}
+	for _, target := range targets {
+		inputProvider.Set(target)
+	}
+	span.SetAttributes(attribute.Int("nuclei.input_count", inputProvider.Count()))
+	return inputProvider
+}
+
 // Close all resources used by nuclei engine
 func (e *ThreadSafeNucleiEngine) Close() {
 	e.eng.Close()
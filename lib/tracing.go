@@ -0,0 +1,30 @@
+package nuclei
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies spans emitted by this package in an OTel backend.
+const tracerName = "github.com/projectdiscovery/nuclei/v3/lib"
+
+// WithTracer sets the TracerProvider used to create spans around template
+// loading, target loading and scan execution. If unset, the global
+// otel.GetTracerProvider() is used, which is a no-op until the caller wires
+// up a real exporter (Jaeger, Tempo, OTLP, ...).
+func WithTracer(provider trace.TracerProvider) NucleiSDKOptions {
+	return func(e *NucleiEngine) error {
+		e.tracerProvider = provider
+		return nil
+	}
+}
+
+// tracer returns the engine's configured tracer, falling back to the
+// global provider so instrumentation is always safe to call.
+func (e *NucleiEngine) tracer() trace.Tracer {
+	provider := e.tracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return provider.Tracer(tracerName)
+}
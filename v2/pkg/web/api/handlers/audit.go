@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/projectdiscovery/nuclei/v2/pkg/web/audit"
+)
+
+// errEmptyInt marks a missing or non-positive pagination parameter so
+// callers can fall back to its default.
+var errEmptyInt = errors.New("handlers: value is empty or non-positive")
+
+// GetAuditLog handlers /audit, returning audit entries filtered by actor,
+// resource type, action and time range, paginated.
+func (s *Server) GetAuditLog(ctx echo.Context) error {
+	filter := audit.Filter{
+		Actor:        ctx.QueryParam("actor"),
+		ResourceType: ctx.QueryParam("resource-type"),
+		Action:       audit.Action(ctx.QueryParam("action")),
+	}
+	if since := ctx.QueryParam("since"); since != "" {
+		parsed, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return echo.NewHTTPError(400, "invalid since: "+err.Error())
+		}
+		filter.Since = parsed
+	}
+	if until := ctx.QueryParam("until"); until != "" {
+		parsed, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return echo.NewHTTPError(400, "invalid until: "+err.Error())
+		}
+		filter.Until = parsed
+	}
+	if page, err := parsePositiveInt(ctx.QueryParam("page")); err == nil {
+		filter.Page = page
+	}
+	if perPage, err := parsePositiveInt(ctx.QueryParam("per-page")); err == nil {
+		filter.PerPage = perPage
+	}
+
+	entries, err := s.audit.ListEntries(ctx.Request().Context(), filter)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(200, entries)
+}
+
+// parsePositiveInt parses value as a positive int, returning an error for
+// empty or non-positive input so callers can fall back to a default.
+func parsePositiveInt(value string) (int, error) {
+	if value == "" {
+		return 0, errEmptyInt
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if n <= 0 {
+		return 0, errEmptyInt
+	}
+	return n, nil
+}
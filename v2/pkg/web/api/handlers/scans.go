@@ -3,13 +3,18 @@ package handlers
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"net/http"
 	"strconv"
 	"time"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/labstack/echo/v4"
 	"github.com/projectdiscovery/nuclei/v2/pkg/web/api/services/scans"
+	"github.com/projectdiscovery/nuclei/v2/pkg/web/audit"
 	"github.com/projectdiscovery/nuclei/v2/pkg/web/db/dbsql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 // AddScanRequest is a request for /scans addition
@@ -37,6 +42,11 @@ func (s *Server) AddScan(ctx echo.Context) error {
 		targets[i] = value
 	}
 	hostCount := scans.CalculateTargetCount(req.Targets, s.db)
+
+	carrier := propagation.HeaderCarrier(http.Header{})
+	otel.GetTextMapPropagator().Inject(ctx.Request().Context(), carrier)
+	traceParent := carrier.Get("traceparent")
+
 	id, err := s.db.Queries().AddScan(context.Background(), dbsql.AddScanParams{
 		Name:              sql.NullString{String: req.Name, Valid: true},
 		Status:            sql.NullString{String: "scheduled", Valid: true},
@@ -49,10 +59,17 @@ func (s *Server) AddScan(ctx echo.Context) error {
 		Reporting:         sql.NullString{String: req.Reporting, Valid: true},
 		Scheduleoccurence: sql.NullString{String: req.ScheduleOccurence, Valid: true},
 		Scheduletime:      sql.NullString{String: req.ScheduleTime, Valid: true},
+		Traceparent:       sql.NullString{String: traceParent, Valid: traceParent != ""},
 	})
 
+	// Register the recurring schedule (if any); Schedule adopts any row with
+	// a schedule_occurence that is not canceled, whether or not the RunNow
+	// Queue below has already flipped it to "queued".
+	if err == nil && req.ScheduleOccurence != "" {
+		_ = s.scheduler.Schedule(ctx.Request().Context(), id)
+	}
 	if req.RunNow {
-		s.scans.Queue(scans.ScanRequest{
+		s.scans.Queue(ctx.Request().Context(), scans.ScanRequest{
 			ScanID:    id,
 			Templates: req.Templates,
 			Targets:   req.Targets,
@@ -61,9 +78,69 @@ func (s *Server) AddScan(ctx echo.Context) error {
 			Reporting: req.Reporting,
 		})
 	}
+	if err == nil {
+		_ = s.audit.Record(ctx, audit.ActionCreate, "scan", id, nil, GetScanResponse{
+			ID:                id,
+			Status:            "scheduled",
+			Name:              req.Name,
+			Templates:         req.Templates,
+			Targets:           req.Targets,
+			Config:            req.Config,
+			RunNow:            req.RunNow,
+			Reporting:         req.Reporting,
+			ScheduleOccurence: req.ScheduleOccurence,
+			ScheduleTime:      req.ScheduleTime,
+			ScanSource:        req.ScanSource,
+		})
+	}
 	return err
 }
 
+// UpdateScan handlers /scans/:id update route, recording a field-level
+// diff of what changed in the audit log.
+func (s *Server) UpdateScan(ctx echo.Context) error {
+	id, err := parseScanID(ctx)
+	if err != nil {
+		return err
+	}
+
+	before, err := s.getScanResponse(id)
+	if err != nil {
+		return err
+	}
+
+	var req AddScanRequest
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&req); err != nil {
+		return err
+	}
+
+	hostCount := scans.CalculateTargetCount(req.Targets, s.db)
+	if err := s.db.Queries().UpdateScan(context.Background(), dbsql.UpdateScanParams{
+		ID:                id,
+		Name:              sql.NullString{String: req.Name, Valid: true},
+		Hosts:             sql.NullInt64{Int64: hostCount, Valid: true},
+		Templates:         req.Templates,
+		Targets:           req.Targets,
+		Config:            sql.NullString{String: req.Config, Valid: true},
+		Runnow:            sql.NullBool{Bool: req.RunNow, Valid: true},
+		Reporting:         sql.NullString{String: req.Reporting, Valid: true},
+		Scheduleoccurence: sql.NullString{String: req.ScheduleOccurence, Valid: true},
+		Scheduletime:      sql.NullString{String: req.ScheduleTime, Valid: true},
+	}); err != nil {
+		return err
+	}
+	_ = s.scheduler.Schedule(ctx.Request().Context(), id)
+
+	after, err := s.getScanResponse(id)
+	if err != nil {
+		return err
+	}
+	if err := s.audit.Record(ctx, audit.ActionUpdate, "scan", id, before, after); err != nil {
+		return err
+	}
+	return ctx.JSON(200, after)
+}
+
 // GetScanResponse is a response for /scans request
 type GetScanResponse struct {
 	ID                int64         `json:"id"`
@@ -77,8 +154,19 @@ type GetScanResponse struct {
 	ScheduleOccurence string        `json:"schedule-occurence"`
 	ScheduleTime      string        `json:"schedule-time"`
 	ScanSource        string        `json:"scanSource"`
-	ScanTime          time.Duration `json:"scanTime"`
-	Hosts             int64         `json:"hosts"`
+	ScanTime          time.Duration `json:"scanTime" audit:"-"`
+	Hosts             int64         `json:"hosts" audit:"-"`
+	LastRunAt         *time.Time    `json:"last-run-at,omitempty" audit:"-"`
+	NextRunAt         *time.Time    `json:"next-run-at,omitempty" audit:"-"`
+}
+
+// nullTimePtr converts a sql.NullTime into a *time.Time, omitempty-friendly
+// for scans that have never run or aren't scheduled.
+func nullTimePtr(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
 }
 
 // GetScans handlers /scans getting route
@@ -109,23 +197,28 @@ func (s *Server) GetScans(ctx echo.Context) error {
 			ScanSource:        value.Scansource.String,
 			ScanTime:          time.Duration(value.Scantime.Int64),
 			Hosts:             value.Hosts.Int64,
+			LastRunAt:         nullTimePtr(value.Lastrunat),
+			NextRunAt:         nullTimePtr(value.Nextrunat),
 		}
 	}
 	return ctx.JSON(200, targets)
 }
 
-// GetScan handlers /scans/:id getting route
-func (s *Server) GetScan(ctx echo.Context) error {
-	queryParam := ctx.Param("id")
-	id, err := strconv.ParseInt(queryParam, 10, 64)
-	if err != nil {
-		return err
-	}
+// parseScanID extracts and parses the :id path parameter shared by every
+// per-scan route.
+func parseScanID(ctx echo.Context) (int64, error) {
+	return strconv.ParseInt(ctx.Param("id"), 10, 64)
+}
+
+// getScanResponse loads scan id and converts it to a GetScanResponse, shared
+// by GetScan and UpdateScan (the latter needs it both before and after
+// applying a change, to build an audit diff).
+func (s *Server) getScanResponse(id int64) (GetScanResponse, error) {
 	scan, err := s.db.Queries().GetScan(context.Background(), id)
 	if err != nil {
-		return err
+		return GetScanResponse{}, err
 	}
-	value := GetScanResponse{
+	return GetScanResponse{
 		ID:                scan.ID,
 		Status:            scan.Status.String,
 		Name:              scan.Name.String,
@@ -139,11 +232,138 @@ func (s *Server) GetScan(ctx echo.Context) error {
 		ScanSource:        scan.Scansource.String,
 		ScanTime:          time.Duration(scan.Scantime.Int64),
 		Hosts:             scan.Hosts.Int64,
+		LastRunAt:         nullTimePtr(scan.Lastrunat),
+		NextRunAt:         nullTimePtr(scan.Nextrunat),
+	}, nil
+}
+
+// GetScan handlers /scans/:id getting route
+func (s *Server) GetScan(ctx echo.Context) error {
+	id, err := parseScanID(ctx)
+	if err != nil {
+		return err
+	}
+	value, err := s.getScanResponse(id)
+	if err != nil {
+		return err
 	}
 	return ctx.JSON(200, value)
 }
 
-// GetScanProgress handlers /scans/progress getting route
-func (s *Server) GetScanProgress(ctx echo.Context) error {
-	return ctx.JSON(200, s.scans.Progress())
+// AcquireScanJobRequest is a request for /scans/acquire, issued by a worker
+// that wants to claim the next available scan job.
+type AcquireScanJobRequest struct {
+	WorkerID string `json:"worker-id"`
+}
+
+// AcquireScanJobResponse is the job handed back to a worker by AcquireScanJob.
+type AcquireScanJobResponse struct {
+	ScanID    int64    `json:"id"`
+	Templates []string `json:"templates"`
+	Targets   []string `json:"targets"`
+	Config    string   `json:"config"`
+	Reporting string   `json:"reporting-config"`
+	// TraceParent is the W3C traceparent of the request that queued this
+	// scan, if any. A worker should extract it with
+	// otel.GetTextMapPropagator().Extract and start its execution span as
+	// a child of it, so the scan's trace stays linked to the request that
+	// triggered it.
+	TraceParent string `json:"trace-parent,omitempty"`
+}
+
+// AcquireScanJob handlers /scans/acquire, long-polling until a queued
+// scan job becomes available or the caller disconnects.
+func (s *Server) AcquireScanJob(ctx echo.Context) error {
+	var req AcquireScanJobRequest
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&req); err != nil {
+		return err
+	}
+	if req.WorkerID == "" {
+		return echo.NewHTTPError(400, "worker-id is required")
+	}
+
+	job, err := s.scans.AcquireScanJob(ctx.Request().Context(), req.WorkerID)
+	if errors.Is(err, scans.ErrNoJobAvailable) {
+		return ctx.NoContent(204)
+	}
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(200, AcquireScanJobResponse{
+		ScanID:      job.ScanID,
+		Templates:   job.Templates,
+		Targets:     job.Targets,
+		Config:      job.Config,
+		Reporting:   job.Reporting,
+		TraceParent: job.TraceParent,
+	})
+}
+
+// UpdateScanJobRequest is a worker heartbeat sent while a scan is running.
+type UpdateScanJobRequest struct {
+	WorkerID string  `json:"worker-id"`
+	Percent  float64 `json:"percent"`
+}
+
+// UpdateScanJobResponse tells the worker whether it should keep running.
+type UpdateScanJobResponse struct {
+	Continue bool `json:"continue"`
+}
+
+// UpdateScanJob handlers /scans/:id/heartbeat, extending a worker's lease
+// on a scan job and reporting whether the scan has since been canceled.
+func (s *Server) UpdateScanJob(ctx echo.Context) error {
+	id, err := parseScanID(ctx)
+	if err != nil {
+		return err
+	}
+	var req UpdateScanJobRequest
+	if err := jsoniter.NewDecoder(ctx.Request().Body).Decode(&req); err != nil {
+		return err
+	}
+
+	shouldContinue, err := s.scans.UpdateScanJob(ctx.Request().Context(), id, req.WorkerID, req.Percent)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(200, UpdateScanJobResponse{Continue: shouldContinue})
+}
+
+// DeleteScan handlers /scans/:id deletion route.
+func (s *Server) DeleteScan(ctx echo.Context) error {
+	id, err := parseScanID(ctx)
+	if err != nil {
+		return err
+	}
+
+	before, err := s.getScanResponse(id)
+	if err != nil {
+		return err
+	}
+	if err := s.db.Queries().DeleteScan(context.Background(), id); err != nil {
+		return err
+	}
+	if err := s.audit.Record(ctx, audit.ActionDelete, "scan", id, before, nil); err != nil {
+		return err
+	}
+	return ctx.NoContent(204)
+}
+
+// CancelScan handlers /scans/:id/cancel, removing a queued scan job or
+// signaling a running worker to stop at its next heartbeat. It also drops
+// the scan's scheduler heap entry, if any, so a canceled recurring scan
+// doesn't get resurrected the next time its cron schedule fires.
+func (s *Server) CancelScan(ctx echo.Context) error {
+	id, err := parseScanID(ctx)
+	if err != nil {
+		return err
+	}
+	if err := s.scans.CancelScanJob(ctx.Request().Context(), id); err != nil {
+		return err
+	}
+	s.scheduler.Unschedule(id)
+	if err := s.audit.Record(ctx, audit.ActionCancel, "scan", id, nil, nil); err != nil {
+		return err
+	}
+	return ctx.NoContent(204)
 }
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// StreamScan handlers /scans/:id/stream, a server-sent-events endpoint that
+// subscribes to the scan's event bus topic and pushes every lifecycle
+// event (start, progress, result, finished) to the client as it happens.
+// This is the only way to observe a running scan's progress; there is no
+// polling endpoint.
+func (s *Server) StreamScan(ctx echo.Context) error {
+	id, err := parseScanID(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := ctx.Request()
+	resp := ctx.Response()
+
+	events, err := s.scans.EventBus().Subscribe(req.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	resp.Header().Set(echo.HeaderContentType, "text/event-stream")
+	resp.Header().Set("Cache-Control", "no-cache")
+	resp.Header().Set("Connection", "keep-alive")
+	resp.WriteHeader(200)
+
+	for event := range events {
+		if _, err := fmt.Fprintf(resp, "data: %s\n\n", event.Payload); err != nil {
+			return nil
+		}
+		resp.Flush()
+	}
+	return nil
+}
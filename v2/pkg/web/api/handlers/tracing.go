@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies spans emitted by the web API handlers in an OTel backend.
+const tracerName = "github.com/projectdiscovery/nuclei/v2/pkg/web/api/handlers"
+
+// TracingMiddleware extracts a W3C traceparent header from the incoming
+// request (if any) and starts a span for the route as its child, replacing
+// ctx.Request() with one carrying the derived context so downstream calls
+// (s.scans.Queue, s.audit.Record, ...) can attach their own child spans
+// instead of starting disconnected traces.
+func TracingMiddleware() echo.MiddlewareFunc {
+	propagator := otel.GetTextMapPropagator()
+	tracer := otel.GetTracerProvider().Tracer(tracerName)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			req := ctx.Request()
+			parentCtx := propagator.Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			spanCtx, span := tracer.Start(parentCtx, ctx.Path(), trace.WithAttributes(
+				attribute.String("http.method", req.Method),
+				attribute.String("http.route", ctx.Path()),
+			))
+			defer span.End()
+
+			ctx.SetRequest(req.WithContext(spanCtx))
+			err := next(ctx)
+			if err != nil {
+				span.RecordError(err)
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package scans
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// eventBufferSize is how many events a subscriber channel can buffer; once
+// it is full, Publish drops each new event for that subscriber to stay
+// non-blocking.
+const eventBufferSize = 64
+
+// ringBufferSize is how many past events are replayed to a subscriber that
+// connects after a scan has already started publishing.
+const ringBufferSize = 50
+
+// Event is a single pub/sub message published for a scan. Payload is the
+// JSON-encoded lib.ScanEvent produced by the worker executing the scan.
+type Event struct {
+	ScanID  int64
+	Payload json.RawMessage
+}
+
+// ScanEventBus publishes and subscribes to scan lifecycle events. The
+// in-process implementation is sufficient for a single node; the Postgres
+// implementation fans events out across every node sharing the database,
+// which is required once AcquireScanJob allows workers to run anywhere.
+type ScanEventBus interface {
+	// Publish sends event to every current and future subscriber of scanID.
+	Publish(scanID int64, event Event) error
+	// Subscribe returns a channel of events for scanID, pre-seeded with up
+	// to ringBufferSize previously published events so late subscribers
+	// don't miss the start of a scan. The channel is closed when ctx is
+	// canceled or Unsubscribe-equivalent cleanup happens.
+	Subscribe(ctx context.Context, scanID int64) (<-chan Event, error)
+	// Evict closes scanID's topic, closing every still-connected subscriber
+	// channel. Callers should invoke this once a scan reaches a terminal
+	// state so a long-running server doesn't accumulate a ring buffer and
+	// subscriber map per scan it has ever run.
+	Evict(scanID int64)
+}
+
+// InProcessEventBus is a ScanEventBus backed by in-memory channels. It only
+// delivers events published and subscribed to within the same process.
+type InProcessEventBus struct {
+	mu     sync.Mutex
+	topics map[int64]*topic
+}
+
+type topic struct {
+	subscribers map[chan Event]struct{}
+	ring        []Event
+}
+
+// NewInProcessEventBus creates an empty in-process scan event bus.
+func NewInProcessEventBus() *InProcessEventBus {
+	return &InProcessEventBus{topics: make(map[int64]*topic)}
+}
+
+// Publish implements ScanEventBus.
+func (b *InProcessEventBus) Publish(scanID int64, event Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t, ok := b.topics[scanID]
+	if !ok {
+		t = &topic{subscribers: make(map[chan Event]struct{})}
+		b.topics[scanID] = t
+	}
+	t.ring = append(t.ring, event)
+	if len(t.ring) > ringBufferSize {
+		t.ring = t.ring[len(t.ring)-ringBufferSize:]
+	}
+
+	for ch := range t.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// slow subscriber: drop the event rather than block the publisher
+		}
+	}
+	return nil
+}
+
+// Subscribe implements ScanEventBus.
+func (b *InProcessEventBus) Subscribe(ctx context.Context, scanID int64) (<-chan Event, error) {
+	b.mu.Lock()
+	t, ok := b.topics[scanID]
+	if !ok {
+		t = &topic{subscribers: make(map[chan Event]struct{})}
+		b.topics[scanID] = t
+	}
+	ch := make(chan Event, eventBufferSize)
+	for _, event := range t.ring {
+		ch <- event
+	}
+	t.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		if _, ok := t.subscribers[ch]; ok {
+			delete(t.subscribers, ch)
+			close(ch)
+		}
+		b.mu.Unlock()
+	}()
+	return ch, nil
+}
+
+// Evict implements ScanEventBus, removing scanID's topic and closing every
+// subscriber channel still registered on it.
+func (b *InProcessEventBus) Evict(scanID int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	t, ok := b.topics[scanID]
+	if !ok {
+		return
+	}
+	for ch := range t.subscribers {
+		delete(t.subscribers, ch)
+		close(ch)
+	}
+	delete(b.topics, scanID)
+}
@@ -0,0 +1,118 @@
+package scans
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// pgChannel is the Postgres NOTIFY channel every node LISTENs on. Payloads
+// are tagged with the scan ID so a single channel can multiplex every scan.
+const pgChannel = "nuclei_scan_events"
+
+const (
+	minReconnectInterval = 10 * time.Second
+	maxReconnectInterval = time.Minute
+)
+
+// pgEventEnvelope is the wire format sent over LISTEN/NOTIFY, which only
+// accepts a single text payload per notification. Evict marks a terminal
+// signal rather than a scan event - relay routes it to local.Evict instead
+// of local.Publish so every node drops the topic, not just the one whose
+// worker ran the scan.
+type pgEventEnvelope struct {
+	ScanID  int64           `json:"scan_id"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	Evict   bool            `json:"evict,omitempty"`
+}
+
+// PostgresEventBus is a ScanEventBus backed by Postgres LISTEN/NOTIFY, so
+// that scan events published on one node are delivered to subscribers
+// connected to any other node sharing the same database. Local delivery is
+// still fanned out through an InProcessEventBus so Subscribe doesn't need
+// its own per-call LISTEN.
+type PostgresEventBus struct {
+	db       *sql.DB
+	local    *InProcessEventBus
+	listener *pq.Listener
+}
+
+// NewPostgresEventBus creates a PostgresEventBus that NOTIFYs over db's
+// connection string and starts listening on pgChannel. Callers should call
+// Close when the server shuts down.
+func NewPostgresEventBus(db *sql.DB, connString string) (*PostgresEventBus, error) {
+	listener := pq.NewListener(connString, minReconnectInterval, maxReconnectInterval, nil)
+	if err := listener.Listen(pgChannel); err != nil {
+		return nil, fmt.Errorf("scans: failed to listen on %s: %w", pgChannel, err)
+	}
+
+	b := &PostgresEventBus{
+		db:       db,
+		local:    NewInProcessEventBus(),
+		listener: listener,
+	}
+	go b.relay()
+	return b, nil
+}
+
+// relay reads NOTIFY payloads from Postgres and republishes them to local
+// subscribers via the in-process bus, or evicts the local topic if the
+// payload signals that the scan it's for has reached a terminal state.
+func (b *PostgresEventBus) relay() {
+	for notification := range b.listener.Notify {
+		if notification == nil {
+			continue
+		}
+		var envelope pgEventEnvelope
+		if err := json.Unmarshal([]byte(notification.Extra), &envelope); err != nil {
+			continue
+		}
+		if envelope.Evict {
+			b.local.Evict(envelope.ScanID)
+			continue
+		}
+		_ = b.local.Publish(envelope.ScanID, Event{ScanID: envelope.ScanID, Payload: envelope.Payload})
+	}
+}
+
+// Publish implements ScanEventBus by issuing a Postgres NOTIFY; every node
+// listening on pgChannel (including this one, via relay) receives it.
+func (b *PostgresEventBus) Publish(scanID int64, event Event) error {
+	envelope, err := json.Marshal(pgEventEnvelope{ScanID: scanID, Payload: event.Payload})
+	if err != nil {
+		return err
+	}
+	_, err = b.db.Exec(`SELECT pg_notify($1, $2)`, pgChannel, string(envelope))
+	return err
+}
+
+// Subscribe implements ScanEventBus by delegating to the local in-process
+// bus, which relay() keeps fed from Postgres notifications.
+func (b *PostgresEventBus) Subscribe(ctx context.Context, scanID int64) (<-chan Event, error) {
+	return b.local.Subscribe(ctx, scanID)
+}
+
+// Evict implements ScanEventBus by evicting scanID's topic locally and
+// NOTIFYing every other node sharing the database to do the same. Without
+// this, a node that never ran scanID's worker - and so never calls Evict
+// itself - would keep its ring buffer and subscriber map for that scan
+// forever, since CompleteScanJob only runs the call on the node that
+// executed the scan.
+func (b *PostgresEventBus) Evict(scanID int64) {
+	b.local.Evict(scanID)
+
+	envelope, err := json.Marshal(pgEventEnvelope{ScanID: scanID, Evict: true})
+	if err != nil {
+		return
+	}
+	_, _ = b.db.Exec(`SELECT pg_notify($1, $2)`, pgChannel, string(envelope))
+}
+
+// Close stops listening for Postgres notifications.
+func (b *PostgresEventBus) Close() error {
+	return b.listener.Close()
+}
@@ -0,0 +1,126 @@
+package scans
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/web/audit"
+	"github.com/projectdiscovery/nuclei/v2/pkg/web/db/dbsql"
+)
+
+// DefaultLeaseDuration is how long a worker holds exclusive ownership of a
+// scan job before another worker is allowed to acquire it. Workers must
+// call UpdateScanJob before the lease expires to keep ownership.
+const DefaultLeaseDuration = time.Minute
+
+// ErrNoJobAvailable is returned by AcquireScanJob when there is currently no
+// queued scan whose lease has expired.
+var ErrNoJobAvailable = errors.New("scans: no job available")
+
+// ScanJob is a unit of work handed out to a worker by AcquireScanJob. It
+// carries everything a worker needs to run the scan without going back to
+// the database.
+type ScanJob struct {
+	ScanID         int64
+	Templates      []string
+	Targets        []string
+	Config         string
+	Reporting      string
+	AcquiredBy     string
+	LeaseExpiresAt time.Time
+	// TraceParent is the W3C traceparent of the request that queued this
+	// scan, if any, so the worker can continue the same trace.
+	TraceParent string
+}
+
+// AcquireScanJob atomically claims the oldest queued scan whose lease has
+// expired (or which has never been acquired) on behalf of workerID, stamping
+// it with a fresh DefaultLeaseDuration lease. It long-polls the database
+// until ctx is canceled, returning ErrNoJobAvailable once that happens
+// without a job becoming available.
+func (s *Service) AcquireScanJob(ctx context.Context, workerID string) (*ScanJob, error) {
+	const pollInterval = time.Second
+
+	for {
+		row, err := s.db.Queries().AcquireScanJob(ctx, dbsql.AcquireScanJobParams{
+			Acquiredby:     sql.NullString{String: workerID, Valid: true},
+			Leaseexpiresat: sql.NullTime{Time: time.Now().Add(DefaultLeaseDuration), Valid: true},
+		})
+		if err == nil {
+			return &ScanJob{
+				ScanID:         row.ID,
+				Templates:      row.Templates,
+				Targets:        row.Targets,
+				Config:         row.Config.String,
+				Reporting:      row.Reporting.String,
+				AcquiredBy:     workerID,
+				LeaseExpiresAt: row.Leaseexpiresat.Time,
+				TraceParent:    row.Traceparent.String,
+			}, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ErrNoJobAvailable
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// UpdateScanJob is the heartbeat a worker sends while executing scanID. It
+// extends the lease by DefaultLeaseDuration; percent is accepted for
+// backwards compatibility with existing workers but is no longer tracked
+// server-side - subscribe to /scans/:id/stream for live progress instead.
+// The returned bool is false once the job has been canceled (via
+// CancelScanJob) or the worker no longer owns the row's lease (it expired
+// and another worker already re-acquired it), either of which the caller
+// should treat as a signal to stop and abandon the scan.
+func (s *Service) UpdateScanJob(ctx context.Context, scanID int64, workerID string, percent float64) (bool, error) {
+	canceled, err := s.db.Queries().HeartbeatScanJob(ctx, dbsql.HeartbeatScanJobParams{
+		ID:             scanID,
+		Acquiredby:     sql.NullString{String: workerID, Valid: true},
+		Leaseexpiresat: sql.NullTime{Time: time.Now().Add(DefaultLeaseDuration), Valid: true},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !canceled, nil
+}
+
+// CancelScanJob marks scanID as canceled so that the worker currently
+// holding its lease (if any) stops at its next heartbeat, and removes it
+// from the queue if it has not yet been acquired.
+func (s *Service) CancelScanJob(ctx context.Context, scanID int64) error {
+	return s.db.Queries().CancelScanJob(ctx, scanID)
+}
+
+// CompleteScanJob marks scanID as finished with status and stamps
+// completed_at, which keeps AcquireScanJob from handing it out again;
+// acquired_by and lease_expires_at are left as they were. The scan's event bus topic is evicted once it
+// reaches this terminal state, since no further events will be published
+// for it and subscribers are expected to disconnect once they see one. The
+// transition is also recorded in the audit log, on behalf of the worker
+// that ran the scan rather than any HTTP caller.
+func (s *Service) CompleteScanJob(ctx context.Context, scanID int64, status string) error {
+	if err := s.db.Queries().CompleteScanJob(ctx, dbsql.CompleteScanJobParams{
+		ID:     scanID,
+		Status: nullString(status),
+	}); err != nil {
+		return err
+	}
+	if s.audit != nil {
+		_ = s.audit.RecordSystem(ctx, "worker", audit.ActionStatusChange, "scan", scanID, []audit.FieldDiff{
+			{Field: "Status", New: status},
+		})
+	}
+	s.bus.Evict(scanID)
+	return nil
+}
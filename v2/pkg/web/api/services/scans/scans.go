@@ -0,0 +1,78 @@
+// Package scans implements the scan queue service shared by the web API
+// handlers and the scan worker pool.
+package scans
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/web/audit"
+	"github.com/projectdiscovery/nuclei/v2/pkg/web/db/dbsql"
+)
+
+// ScanRequest is a single unit of scan work that workers pick up and execute.
+type ScanRequest struct {
+	ScanID    int64
+	Templates []string
+	Targets   []string
+	Config    string
+	RunNow    bool
+	Reporting string
+}
+
+// Service is the scan queue service. It persists queued scans to the
+// database and hands them out to workers via AcquireScanJob.
+type Service struct {
+	db    *dbsql.Database
+	bus   ScanEventBus
+	audit *audit.Logger
+}
+
+// NewService creates a new scan queue service backed by db, publishing scan
+// lifecycle events to bus and terminal status transitions to auditLogger.
+// Pass NewInProcessEventBus() for a single-node deployment, or
+// NewPostgresEventBus(...) when multiple nodes share db.
+func NewService(db *dbsql.Database, bus ScanEventBus, auditLogger *audit.Logger) *Service {
+	return &Service{
+		db:    db,
+		bus:   bus,
+		audit: auditLogger,
+	}
+}
+
+// EventBus returns the bus used to publish and subscribe to scan events.
+func (s *Service) EventBus() ScanEventBus {
+	return s.bus
+}
+
+// PublishEvent publishes a raw lib.ScanEvent payload for scanID on the
+// service's event bus. Workers call this from their lib.ScanEventCallback so
+// that scan progress and results are visible to /scans/:id/stream
+// subscribers as soon as they happen.
+func (s *Service) PublishEvent(scanID int64, payload json.RawMessage) error {
+	return s.bus.Publish(scanID, Event{ScanID: scanID, Payload: payload})
+}
+
+// Queue marks a scan row "queued" and clears any previous lease it might
+// still be holding (e.g. on a resubmit), which is what actually makes it
+// visible to AcquireScanJob - a freshly inserted row sits in "scheduled"
+// until RunNow or the scheduler firing a cron occurrence calls this. ctx
+// carries the caller's trace context so the query shows up as a child of
+// the request/scheduler span that triggered it.
+func (s *Service) Queue(ctx context.Context, req ScanRequest) error {
+	return s.db.Queries().ReleaseScanJobLease(ctx, req.ScanID)
+}
+
+// CalculateTargetCount returns the number of hosts a scan would run against.
+// db is accepted for a future uploaded-target-list expansion - there is no
+// such table yet, so it is currently unused and targets is taken at face
+// value, one host per entry.
+func CalculateTargetCount(targets []string, db *dbsql.Database) int64 {
+	return int64(len(targets))
+}
+
+// nullString is a small convenience wrapper used when building dbsql params.
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
@@ -0,0 +1,150 @@
+// Package audit records who did what to scans, for operators who need a
+// forensic trail of scheduling and configuration changes.
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/projectdiscovery/nuclei/v2/pkg/web/db/dbsql"
+)
+
+// Action identifies what happened to a resource.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+	ActionCancel Action = "cancel"
+	// ActionStatusChange records a terminal status transition (completed,
+	// failed, etc.) observed by the worker executing a scan.
+	ActionStatusChange Action = "status-change"
+)
+
+// Entry is a single audit log row.
+type Entry struct {
+	ID           int64       `json:"id"`
+	Actor        string      `json:"actor"`
+	Action       Action      `json:"action"`
+	ResourceType string      `json:"resource-type"`
+	ResourceID   int64       `json:"resource-id"`
+	Timestamp    time.Time   `json:"timestamp"`
+	RequestIP    string      `json:"request-ip"`
+	UserAgent    string      `json:"user-agent"`
+	Diff         []FieldDiff `json:"diff,omitempty"`
+}
+
+// Logger persists audit entries to the database.
+type Logger struct {
+	db *dbsql.Database
+}
+
+// NewLogger creates an audit Logger backed by db.
+func NewLogger(db *dbsql.Database) *Logger {
+	return &Logger{db: db}
+}
+
+// Record writes an audit entry for action on resourceType/resourceID,
+// identifying the actor and request metadata from ctx. old and new are
+// diffed with DiffStructs to populate Entry.Diff; if either is nil (a
+// create or delete) it is treated as the zero value of the other's type,
+// so the diff still captures every initial or removed field rather than
+// coming back empty.
+func (l *Logger) Record(ctx echo.Context, action Action, resourceType string, resourceID int64, old, new any) error {
+	diff := diffAgainstZero(old, new)
+	return l.record(ctx.Request().Context(), actorFromContext(ctx), ctx.RealIP(), ctx.Request().UserAgent(), action, resourceType, resourceID, diff)
+}
+
+// RecordSystem writes an audit entry on behalf of actor outside of any HTTP
+// request - e.g. a worker reporting the terminal status transition of a
+// scan job it just finished, which has no echo.Context to pull a real
+// actor/IP/user-agent from.
+func (l *Logger) RecordSystem(ctx context.Context, actor string, action Action, resourceType string, resourceID int64, diff []FieldDiff) error {
+	return l.record(ctx, actor, "", "", action, resourceType, resourceID, diff)
+}
+
+func (l *Logger) record(ctx context.Context, actor, requestIP, userAgent string, action Action, resourceType string, resourceID int64, diff []FieldDiff) error {
+	diffJSON, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.db.Queries().AddAuditEntry(ctx, dbsql.AddAuditEntryParams{
+		Actor:        sql.NullString{String: actor, Valid: actor != ""},
+		Action:       sql.NullString{String: string(action), Valid: true},
+		Resourcetype: sql.NullString{String: resourceType, Valid: true},
+		Resourceid:   resourceID,
+		Requestip:    sql.NullString{String: requestIP, Valid: requestIP != ""},
+		Useragent:    sql.NullString{String: userAgent, Valid: userAgent != ""},
+		Diff:         diffJSON,
+	})
+	return err
+}
+
+// actorFromContext extracts the authenticated actor's identity set on the
+// request context by the auth middleware. Falls back to "unknown" so
+// audit entries are never silently dropped for lack of an actor.
+func actorFromContext(ctx echo.Context) string {
+	if actor, ok := ctx.Get("actor").(string); ok && actor != "" {
+		return actor
+	}
+	return "unknown"
+}
+
+// Filter narrows a ListEntries query.
+type Filter struct {
+	Actor        string
+	ResourceType string
+	Action       Action
+	Since        time.Time
+	Until        time.Time
+	Page         int
+	PerPage      int
+}
+
+// ListEntries returns audit entries matching filter, newest first.
+func (l *Logger) ListEntries(ctx context.Context, filter Filter) ([]Entry, error) {
+	perPage := filter.PerPage
+	if perPage <= 0 {
+		perPage = 50
+	}
+	page := filter.Page
+	if page <= 0 {
+		page = 1
+	}
+
+	rows, err := l.db.Queries().ListAuditEntries(ctx, dbsql.ListAuditEntriesParams{
+		Actor:        sql.NullString{String: filter.Actor, Valid: filter.Actor != ""},
+		Resourcetype: sql.NullString{String: filter.ResourceType, Valid: filter.ResourceType != ""},
+		Action:       sql.NullString{String: string(filter.Action), Valid: filter.Action != ""},
+		Since:        sql.NullTime{Time: filter.Since, Valid: !filter.Since.IsZero()},
+		Until:        sql.NullTime{Time: filter.Until, Valid: !filter.Until.IsZero()},
+		Limit:        int64(perPage),
+		Offset:       int64((page - 1) * perPage),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, len(rows))
+	for i, row := range rows {
+		var diff []FieldDiff
+		_ = json.Unmarshal(row.Diff, &diff)
+		entries[i] = Entry{
+			ID:           row.ID,
+			Actor:        row.Actor.String,
+			Action:       Action(row.Action.String),
+			ResourceType: row.Resourcetype.String,
+			ResourceID:   row.Resourceid,
+			Timestamp:    row.Createdat.Time,
+			RequestIP:    row.Requestip.String,
+			UserAgent:    row.Useragent.String,
+			Diff:         diff,
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldDiff is a single changed field between two versions of a resource.
+// For slice fields, Old/New hold only the removed/added elements rather
+// than the full before/after slices.
+type FieldDiff struct {
+	Field string `json:"field"`
+	Old   any    `json:"old,omitempty"`
+	New   any    `json:"new,omitempty"`
+}
+
+// diffAgainstZero diffs old and new with DiffStructs, substituting the zero
+// value of the other side's type for a nil old or new so that a create
+// (old == nil) or delete (new == nil) still produces a full field-level
+// diff instead of none at all. Returns nil if both are nil.
+func diffAgainstZero(old, new any) []FieldDiff {
+	switch {
+	case old != nil && new != nil:
+		return DiffStructs(old, new)
+	case old != nil:
+		return DiffStructs(old, reflect.Zero(reflect.TypeOf(old)).Interface())
+	case new != nil:
+		return DiffStructs(reflect.Zero(reflect.TypeOf(new)).Interface(), new)
+	default:
+		return nil
+	}
+}
+
+// DiffStructs walks the exported fields of old and new, which must be the
+// same struct type, and returns a FieldDiff for every field whose value
+// changed. Fields tagged `audit:"-"` are skipped entirely. Slice fields are
+// compared as sets: Old lists elements present in old but not new (removed),
+// New lists elements present in new but not old (added).
+func DiffStructs(old, new any) []FieldDiff {
+	oldVal := reflect.ValueOf(old)
+	newVal := reflect.ValueOf(new)
+	if oldVal.Kind() != reflect.Struct || newVal.Kind() != reflect.Struct || oldVal.Type() != newVal.Type() {
+		return nil
+	}
+
+	var diffs []FieldDiff
+	t := oldVal.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("audit") == "-" {
+			continue
+		}
+
+		oldField := oldVal.Field(i)
+		newField := newVal.Field(i)
+
+		if oldField.Kind() == reflect.Slice {
+			added, removed := setDiff(oldField, newField)
+			if len(added) > 0 || len(removed) > 0 {
+				diffs = append(diffs, FieldDiff{Field: field.Name, Old: removed, New: added})
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+			diffs = append(diffs, FieldDiff{Field: field.Name, Old: oldField.Interface(), New: newField.Interface()})
+		}
+	}
+	return diffs
+}
+
+// setDiff compares two slices as sets, returning elements added in new and
+// elements removed from old. Element order and duplicates are ignored.
+func setDiff(oldSlice, newSlice reflect.Value) (added, removed []string) {
+	oldSet := toStringSet(oldSlice)
+	newSet := toStringSet(newSlice)
+
+	for value := range newSet {
+		if !oldSet[value] {
+			added = append(added, value)
+		}
+	}
+	for value := range oldSet {
+		if !newSet[value] {
+			removed = append(removed, value)
+		}
+	}
+	return added, removed
+}
+
+func toStringSet(slice reflect.Value) map[string]bool {
+	set := make(map[string]bool, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		set[fmt.Sprintf("%v", slice.Index(i).Interface())] = true
+	}
+	return set
+}
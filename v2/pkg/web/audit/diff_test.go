@@ -0,0 +1,97 @@
+package audit
+
+import "testing"
+
+type diffFixture struct {
+	Name   string
+	Count  int
+	Tags   []string
+	Secret string `audit:"-"`
+}
+
+func TestDiffStructsReportsChangedFields(t *testing.T) {
+	old := diffFixture{Name: "a", Count: 1, Tags: []string{"x"}, Secret: "s1"}
+	new := diffFixture{Name: "b", Count: 1, Tags: []string{"x"}, Secret: "s2"}
+
+	diffs := DiffStructs(old, new)
+	if len(diffs) != 1 {
+		t.Fatalf("got %d diffs, want 1: %+v", len(diffs), diffs)
+	}
+	if diffs[0].Field != "Name" || diffs[0].Old != "a" || diffs[0].New != "b" {
+		t.Errorf("diff = %+v, want Name a->b", diffs[0])
+	}
+}
+
+func TestDiffStructsSkipsIgnoredAndIdenticalFields(t *testing.T) {
+	old := diffFixture{Name: "a", Count: 1, Tags: []string{"x"}, Secret: "s1"}
+	new := diffFixture{Name: "a", Count: 1, Tags: []string{"x"}, Secret: "s2"}
+
+	if diffs := DiffStructs(old, new); len(diffs) != 0 {
+		t.Fatalf("got %d diffs, want 0 (Secret is audit:\"-\", everything else unchanged): %+v", len(diffs), diffs)
+	}
+}
+
+func TestDiffStructsDiffsSlicesAsSets(t *testing.T) {
+	old := diffFixture{Tags: []string{"a", "b"}}
+	new := diffFixture{Tags: []string{"b", "c"}}
+
+	diffs := DiffStructs(old, new)
+	if len(diffs) != 1 || diffs[0].Field != "Tags" {
+		t.Fatalf("got %+v, want a single Tags diff", diffs)
+	}
+	added, ok := diffs[0].New.([]string)
+	if !ok || len(added) != 1 || added[0] != "c" {
+		t.Errorf("added = %v, want [c]", diffs[0].New)
+	}
+	removed, ok := diffs[0].Old.([]string)
+	if !ok || len(removed) != 1 || removed[0] != "a" {
+		t.Errorf("removed = %v, want [a]", diffs[0].Old)
+	}
+}
+
+func TestDiffStructsMismatchedTypesReturnsNil(t *testing.T) {
+	if diffs := DiffStructs(diffFixture{}, "not-a-struct"); diffs != nil {
+		t.Errorf("got %+v, want nil for mismatched types", diffs)
+	}
+}
+
+func TestDiffAgainstZeroOnCreateDiffsFromZeroValue(t *testing.T) {
+	new := diffFixture{Name: "a", Count: 1, Tags: []string{"x"}}
+
+	diffs := diffAgainstZero(nil, new)
+	fields := make(map[string]FieldDiff, len(diffs))
+	for _, d := range diffs {
+		fields[d.Field] = d
+	}
+	if d, ok := fields["Name"]; !ok || d.Old != "" || d.New != "a" {
+		t.Errorf("Name diff = %+v, want ''->a", d)
+	}
+	if d, ok := fields["Count"]; !ok || d.Old != 0 || d.New != 1 {
+		t.Errorf("Count diff = %+v, want 0->1", d)
+	}
+	if d, ok := fields["Tags"]; !ok || len(d.New.([]string)) != 1 || d.New.([]string)[0] != "x" {
+		t.Errorf("Tags diff = %+v, want added [x]", d)
+	}
+}
+
+func TestDiffAgainstZeroOnDeleteDiffsToZeroValue(t *testing.T) {
+	old := diffFixture{Name: "a", Count: 1, Tags: []string{"x"}}
+
+	diffs := diffAgainstZero(old, nil)
+	fields := make(map[string]FieldDiff, len(diffs))
+	for _, d := range diffs {
+		fields[d.Field] = d
+	}
+	if d, ok := fields["Name"]; !ok || d.Old != "a" || d.New != "" {
+		t.Errorf("Name diff = %+v, want a->''", d)
+	}
+	if d, ok := fields["Tags"]; !ok || len(d.Old.([]string)) != 1 || d.Old.([]string)[0] != "x" {
+		t.Errorf("Tags diff = %+v, want removed [x]", d)
+	}
+}
+
+func TestDiffAgainstZeroBothNilReturnsNil(t *testing.T) {
+	if diffs := diffAgainstZero(nil, nil); diffs != nil {
+		t.Errorf("got %+v, want nil when both sides are nil", diffs)
+	}
+}
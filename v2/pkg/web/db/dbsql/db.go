@@ -0,0 +1,55 @@
+// Package dbsql contains the sqlc-generated query accessors for the web
+// server's database, generated from the SQL in db/queries against the
+// schema in db/migrations. Do not edit the generated files by hand; add a
+// migration and/or query and regenerate instead.
+package dbsql
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DBTX is satisfied by *sql.DB and *sql.Tx, letting Queries run against
+// either a pooled connection or a transaction.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Queries is the generated query accessor for every *.sql file under
+// db/queries.
+type Queries struct {
+	db DBTX
+}
+
+// New wraps db (typically *sql.DB) in a Queries accessor.
+func New(db DBTX) *Queries {
+	return &Queries{db: db}
+}
+
+// Database is the handle the web server hands to every service; Queries()
+// returns the generated accessor bound to the underlying connection pool.
+type Database struct {
+	conn    *sql.DB
+	queries *Queries
+}
+
+// NewDatabase wraps conn with its generated Queries accessor.
+func NewDatabase(conn *sql.DB) *Database {
+	return &Database{conn: conn, queries: New(conn)}
+}
+
+// Queries returns the generated accessor bound to this database's
+// connection pool.
+func (d *Database) Queries() *Queries {
+	return d.queries
+}
+
+// Conn returns the underlying connection pool, for callers that need to
+// issue SQL outside the generated query set (e.g. PostgresEventBus's
+// `pg_notify`).
+func (d *Database) Conn() *sql.DB {
+	return d.conn
+}
@@ -0,0 +1,47 @@
+package dbsql
+
+import "database/sql"
+
+// Scan is a row of the scans table.
+type Scan struct {
+	ID                int64
+	Name              sql.NullString
+	Status            sql.NullString
+	Hosts             sql.NullInt64
+	Scansource        sql.NullString
+	Templates         []string
+	Targets           []string
+	Config            sql.NullString
+	Runnow            sql.NullBool
+	Reporting         sql.NullString
+	Scheduleoccurence sql.NullString
+	Scheduletime      sql.NullString
+	Scantime          sql.NullInt64
+	// Acquiredby/Acquiredat/Leaseexpiresat/Completedat back the lease-based
+	// job queue: a row is claimable by AcquireScanJob whenever its Status is
+	// "queued", Leaseexpiresat is unset or in the past and Completedat is unset.
+	Acquiredby     sql.NullString
+	Acquiredat     sql.NullTime
+	Leaseexpiresat sql.NullTime
+	Completedat    sql.NullTime
+	// Lastrunat/Nextrunat track a recurring scan's last and next computed
+	// cron occurrence, maintained by the scheduler.
+	Lastrunat sql.NullTime
+	Nextrunat sql.NullTime
+	// Traceparent is the W3C traceparent of the request that queued this
+	// scan, so a worker acquiring it can continue the same trace.
+	Traceparent sql.NullString
+}
+
+// AuditEntry is a row of the audit_log table.
+type AuditEntry struct {
+	ID           int64
+	Actor        sql.NullString
+	Action       sql.NullString
+	Resourcetype sql.NullString
+	Resourceid   int64
+	Requestip    sql.NullString
+	Useragent    sql.NullString
+	Diff         []byte
+	Createdat    sql.NullTime
+}
@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// scheduleEntry is one scan's position in the scheduler's min-heap.
+type scheduleEntry struct {
+	scanID   int64
+	fireAt   time.Time
+	schedule cron.Schedule
+}
+
+// scheduleHeap is a container/heap.Interface ordering entries by fireAt so
+// the next occurrence to run is always at index 0.
+type scheduleHeap []*scheduleEntry
+
+func (h scheduleHeap) Len() int { return len(h) }
+
+func (h scheduleHeap) Less(i, j int) bool { return h[i].fireAt.Before(h[j].fireAt) }
+
+func (h scheduleHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *scheduleHeap) Push(x any) {
+	*h = append(*h, x.(*scheduleEntry))
+}
+
+func (h *scheduleHeap) Pop() any {
+	old := *h
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return entry
+}
@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"container/heap"
+	"testing"
+	"time"
+)
+
+func TestScheduleHeapOrdersByFireAt(t *testing.T) {
+	now := time.Now()
+	h := &scheduleHeap{}
+	heap.Init(h)
+
+	heap.Push(h, &scheduleEntry{scanID: 1, fireAt: now.Add(3 * time.Minute)})
+	heap.Push(h, &scheduleEntry{scanID: 2, fireAt: now.Add(1 * time.Minute)})
+	heap.Push(h, &scheduleEntry{scanID: 3, fireAt: now.Add(2 * time.Minute)})
+
+	var order []int64
+	for h.Len() > 0 {
+		order = append(order, heap.Pop(h).(*scheduleEntry).scanID)
+	}
+
+	want := []int64{2, 3, 1}
+	if len(order) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(order), len(want))
+	}
+	for i, id := range want {
+		if order[i] != id {
+			t.Errorf("pop order[%d] = %d, want %d", i, order[i], id)
+		}
+	}
+}
+
+func TestScheduleHeapRemove(t *testing.T) {
+	now := time.Now()
+	h := &scheduleHeap{}
+	heap.Init(h)
+
+	heap.Push(h, &scheduleEntry{scanID: 1, fireAt: now.Add(time.Minute)})
+	heap.Push(h, &scheduleEntry{scanID: 2, fireAt: now.Add(2 * time.Minute)})
+	heap.Push(h, &scheduleEntry{scanID: 3, fireAt: now.Add(3 * time.Minute)})
+
+	for i, entry := range *h {
+		if entry.scanID == 2 {
+			heap.Remove(h, i)
+			break
+		}
+	}
+
+	if h.Len() != 2 {
+		t.Fatalf("len = %d, want 2", h.Len())
+	}
+	for _, entry := range *h {
+		if entry.scanID == 2 {
+			t.Fatalf("scan 2 still present after Remove")
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Package scheduler turns the ScheduleOccurence/ScheduleTime fields on a
+// scan into recurring runs, queuing a fresh execution each time its cron
+// expression fires.
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/nuclei/v2/pkg/web/api/services/scans"
+	"github.com/projectdiscovery/nuclei/v2/pkg/web/db/dbsql"
+	"github.com/robfig/cron/v3"
+)
+
+// statusCanceled is the terminal status CancelScanJob sets. The scheduler
+// must not queue a fresh occurrence for a row in this state - it tracks a
+// recurring scan by its schedule_occurence column rather than by status,
+// since status keeps changing (queued, running, completed, ...) as each
+// occurrence is picked up and executed.
+const statusCanceled = "canceled"
+
+// Scheduler maintains a min-heap of upcoming scan occurrences and queues
+// each one as it comes due.
+type Scheduler struct {
+	db    *dbsql.Database
+	scans *scans.Service
+	spec  cron.Parser
+
+	mu   sync.Mutex
+	heap scheduleHeap
+	wake chan struct{}
+}
+
+// New creates a Scheduler backed by db, which queues due occurrences onto svc.
+func New(db *dbsql.Database, svc *scans.Service) *Scheduler {
+	return &Scheduler{
+		db:    db,
+		scans: svc,
+		// standard 5-field cron plus the common @hourly/@daily descriptors
+		spec: cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor),
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// Start loads every non-canceled scan that still has a recurring schedule
+// attached - regardless of its other job statuses, since a recurring scan's status
+// moves through queued/running/completed as each occurrence fires and
+// back to "scheduled" only for the very first one - computes its next
+// occurrence from wall clock (so a restart doesn't lose schedules), and
+// runs the fire loop until ctx is canceled.
+func (s *Scheduler) Start(ctx context.Context) error {
+	rows, err := s.db.Queries().GetScansWithSchedule(context.Background())
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	for _, row := range rows {
+		entry, err := s.entryFor(row, now)
+		if err != nil {
+			gologger.Warning().Msgf("scheduler: skipping scan %d with invalid schedule: %s", row.ID, err)
+			continue
+		}
+		if entry != nil {
+			heap.Push(&s.heap, entry)
+		}
+	}
+	s.mu.Unlock()
+
+	go s.loop(ctx)
+	return nil
+}
+
+// entryFor computes the next occurrence for row, honoring AllowedStartWindow
+// so a scan that was due while the scheduler was down doesn't fire a burst
+// of catch-up runs - if the computed occurrence is more than one interval in
+// the past, it is skipped forward to the next one instead.
+func (s *Scheduler) entryFor(row dbsql.Scan, now time.Time) (*scheduleEntry, error) {
+	schedule, err := s.spec.Parse(row.Scheduleoccurence.String)
+	if err != nil {
+		return nil, err
+	}
+
+	anchor := now
+	if row.Scheduletime.Valid {
+		if parsed, err := time.Parse(time.RFC3339, row.Scheduletime.String); err == nil {
+			anchor = parsed
+		}
+	}
+
+	next := schedule.Next(anchor)
+	if missed := next.Before(now); missed {
+		// the naive next occurrence is already in the past (server was down);
+		// one missed by up to an interval is kept and fires right away, while
+		// one more than an interval behind is dropped and recomputed from now
+		// to avoid a stampede of missed runs at boot.
+		interval := schedule.Next(next).Sub(next)
+		if now.Sub(next) > interval {
+			caughtUp := schedule.Next(now)
+			if caughtUp.Sub(now) > interval {
+				caughtUp = now.Add(interval)
+			}
+			next = caughtUp
+		}
+	}
+
+	return &scheduleEntry{scanID: row.ID, fireAt: next, schedule: schedule}, nil
+}
+
+// loop sleeps until the next scheduled occurrence (or a Wake()) and queues
+// whichever scans are due, recomputing their following occurrence.
+func (s *Scheduler) loop(ctx context.Context) {
+	for {
+		s.mu.Lock()
+		var sleep time.Duration
+		if s.heap.Len() == 0 {
+			sleep = time.Minute
+		} else {
+			sleep = time.Until(s.heap[0].fireAt)
+		}
+		s.mu.Unlock()
+		if sleep < 0 {
+			sleep = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(sleep):
+		case <-s.wake:
+		}
+
+		s.fireDue(ctx)
+	}
+}
+
+// fireDue pops and queues every entry whose fireAt has passed, then
+// reschedules each for its following occurrence.
+func (s *Scheduler) fireDue(ctx context.Context) {
+	now := time.Now()
+	s.mu.Lock()
+	var due []*scheduleEntry
+	for s.heap.Len() > 0 && !s.heap[0].fireAt.After(now) {
+		due = append(due, heap.Pop(&s.heap).(*scheduleEntry))
+	}
+	s.mu.Unlock()
+
+	for _, entry := range due {
+		s.fire(ctx, entry)
+
+		entry.fireAt = entry.schedule.Next(entry.fireAt)
+		s.mu.Lock()
+		heap.Push(&s.heap, entry)
+		s.mu.Unlock()
+	}
+}
+
+// fire queues a fresh run for entry's scan and persists last_run_at/next_run_at.
+func (s *Scheduler) fire(ctx context.Context, entry *scheduleEntry) {
+	row, err := s.db.Queries().GetScan(ctx, entry.scanID)
+	if err != nil {
+		gologger.Warning().Msgf("scheduler: could not load scan %d: %s", entry.scanID, err)
+		return
+	}
+	if row.Status.String == statusCanceled {
+		// Canceled between ticks - CancelScan should already have removed
+		// this entry via Unschedule, but skip queuing defensively in case
+		// it raced ahead of that call.
+		return
+	}
+
+	if err := s.scans.Queue(ctx, scans.ScanRequest{
+		ScanID:    row.ID,
+		Templates: row.Templates,
+		Targets:   row.Targets,
+		Config:    row.Config.String,
+		RunNow:    true,
+		Reporting: row.Reporting.String,
+	}); err != nil {
+		gologger.Warning().Msgf("scheduler: could not queue scan %d: %s", entry.scanID, err)
+		return
+	}
+
+	if err := s.db.Queries().UpdateScanScheduleRun(ctx, dbsql.UpdateScanScheduleRunParams{
+		ID:        entry.scanID,
+		Lastrunat: sql.NullTime{Time: time.Now(), Valid: true},
+		Nextrunat: sql.NullTime{Time: entry.schedule.Next(time.Now()), Valid: true},
+	}); err != nil {
+		gologger.Warning().Msgf("scheduler: could not persist run times for scan %d: %s", entry.scanID, err)
+	}
+}
+
+// Schedule (re)computes scanID's next occurrence and pushes it onto the
+// heap, replacing any entry already held for it, then wakes the fire loop.
+// Handlers call this after AddScan/UpdateScan so a scan created or
+// rescheduled after boot fires on its own cron schedule instead of waiting
+// for the next full server restart. Scans with no recurring occurrence
+// (schedule_occurence unset) or that have been canceled are left alone -
+// Queue/RunNow handles the former, Unschedule the latter.
+func (s *Scheduler) Schedule(ctx context.Context, scanID int64) error {
+	row, err := s.db.Queries().GetScan(ctx, scanID)
+	if err != nil {
+		return err
+	}
+	if row.Status.String == statusCanceled || !row.Scheduleoccurence.Valid || row.Scheduleoccurence.String == "" {
+		return nil
+	}
+
+	entry, err := s.entryFor(row, time.Now())
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	for i, existing := range s.heap {
+		if existing.scanID == scanID {
+			heap.Remove(&s.heap, i)
+			break
+		}
+	}
+	if entry != nil {
+		heap.Push(&s.heap, entry)
+	}
+	s.mu.Unlock()
+
+	s.Wake()
+	return nil
+}
+
+// Unschedule removes scanID's heap entry, if any, so a canceled recurring
+// scan's cron schedule stops firing instead of resurrecting it on its next
+// tick. Handlers call this from CancelScan alongside CancelScanJob.
+func (s *Scheduler) Unschedule(scanID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, existing := range s.heap {
+		if existing.scanID == scanID {
+			heap.Remove(&s.heap, i)
+			return
+		}
+	}
+}
+
+// Wake nudges the fire loop to re-check the heap immediately, used after a
+// new scheduled scan is added so it doesn't wait for the next minute tick.
+func (s *Scheduler) Wake() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
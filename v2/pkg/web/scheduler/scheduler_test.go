@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/nuclei/v2/pkg/web/db/dbsql"
+)
+
+func TestEntryForComputesNextOccurrence(t *testing.T) {
+	s := New(nil, nil)
+	now := time.Date(2026, 7, 25, 10, 0, 0, 0, time.UTC)
+
+	row := dbsql.Scan{ID: 1, Scheduleoccurence: sql.NullString{String: "@every 1h", Valid: true}}
+	entry, err := s.entryFor(row, now)
+	if err != nil {
+		t.Fatalf("entryFor: %v", err)
+	}
+	if !entry.fireAt.After(now) {
+		t.Fatalf("fireAt = %s, want after %s", entry.fireAt, now)
+	}
+	if got, want := entry.fireAt.Sub(now), time.Hour; got != want {
+		t.Errorf("fireAt - now = %s, want %s", got, want)
+	}
+}
+
+func TestEntryForCatchesUpWithoutStampede(t *testing.T) {
+	s := New(nil, nil)
+	now := time.Date(2026, 7, 25, 10, 0, 0, 0, time.UTC)
+
+	// anchored ten hours in the past on an hourly schedule: the naive next
+	// occurrence from the anchor is long gone, so entryFor must skip forward
+	// to at most one interval from now rather than firing a burst of missed runs.
+	row := dbsql.Scan{
+		ID:                2,
+		Scheduleoccurence: sql.NullString{String: "@every 1h", Valid: true},
+		Scheduletime:      sql.NullString{String: now.Add(-10 * time.Hour).Format(time.RFC3339), Valid: true},
+	}
+
+	entry, err := s.entryFor(row, now)
+	if err != nil {
+		t.Fatalf("entryFor: %v", err)
+	}
+	if entry.fireAt.Before(now) {
+		t.Fatalf("fireAt = %s, is still in the past relative to %s", entry.fireAt, now)
+	}
+	if max := now.Add(time.Hour); entry.fireAt.After(max) {
+		t.Fatalf("fireAt = %s, want at most one interval past now (%s)", entry.fireAt, max)
+	}
+}
+
+func TestEntryForRejectsInvalidSchedule(t *testing.T) {
+	s := New(nil, nil)
+	row := dbsql.Scan{ID: 3, Scheduleoccurence: sql.NullString{String: "not-a-cron-expr", Valid: true}}
+
+	if _, err := s.entryFor(row, time.Now()); err == nil {
+		t.Fatal("entryFor: expected error for invalid cron expression, got nil")
+	}
+}
+
+func TestUnscheduleRemovesOnlyTheGivenScan(t *testing.T) {
+	s := New(nil, nil)
+	now := time.Now()
+	s.heap = scheduleHeap{
+		{scanID: 1, fireAt: now.Add(time.Minute)},
+		{scanID: 2, fireAt: now.Add(2 * time.Minute)},
+	}
+
+	s.Unschedule(1)
+
+	if s.heap.Len() != 1 {
+		t.Fatalf("len = %d, want 1", s.heap.Len())
+	}
+	if s.heap[0].scanID != 2 {
+		t.Errorf("remaining entry = %d, want 2", s.heap[0].scanID)
+	}
+
+	// Unscheduling an id not in the heap is a no-op, not an error.
+	s.Unschedule(99)
+	if s.heap.Len() != 1 {
+		t.Fatalf("len after no-op Unschedule = %d, want 1", s.heap.Len())
+	}
+}